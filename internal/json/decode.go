@@ -203,6 +203,10 @@ const (
 	EndArr
 	Comma
 	Colon
+	// Comment is only ever produced on the lenient (JSON5/JSONC) parsing
+	// path, and only when the caller opted into preserving comments instead
+	// of having them skipped as whitespace. See ParserOptions.
+	Comment
 )
 
 func Peek(src string, pos int) (ret int, next TokenState) {
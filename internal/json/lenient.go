@@ -0,0 +1,515 @@
+/**
+ * Copyright 2025 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/dynamicgo/internal/rt"
+	"github.com/cloudwego/dynamicgo/internal/types"
+)
+
+// ParserOptions controls optional, non-standard relaxations of the JSON
+// grammar. The zero value parses strict, RFC 8259-compliant JSON and costs
+// nothing extra: every *WithOptions entry point checks Lenient first and,
+// when it is false, falls straight through to the existing strict-mode
+// function so the SIMD fast path on amd64 is never touched by this file.
+type ParserOptions struct {
+	// Lenient enables JSON5/JSONC-style relaxations: line and block
+	// comments, trailing commas before '}' and ']', unquoted identifier
+	// object keys, single-quoted strings, and hex/Infinity/NaN numeric
+	// literals.
+	Lenient bool
+
+	// PreserveComments, when combined with Lenient, makes PeekWithOptions
+	// surface a comment as a Comment token instead of silently skipping it,
+	// so a caller such as a formatter can round-trip it.
+	PreserveComments bool
+}
+
+// identKeyRe matches a bare (unquoted) object key as accepted by JSON5,
+// e.g. foo, _bar, $baz42.
+var identKeyRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*`)
+
+// decodeIdentKey decodes an unquoted object key starting at pos. Callers
+// should only reach this on the lenient path, after confirming the byte at
+// pos cannot start a quoted string.
+func decodeIdentKey(src string, pos int) (ret int, v string) {
+	m := identKeyRe.FindString(src[pos:])
+	if m == "" {
+		return -int(types.ERR_INVALID_CHAR), ""
+	}
+	return pos + len(m), m
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// skipBlankLenient behaves like SkipBlank but additionally treats line
+// comments ("// ... \n") and block comments ("/* ... */") as whitespace.
+// It is reached only from the *WithOptions entry points, so the strict
+// SkipBlank used by Peek, DecodeValue and SkipValue is left untouched.
+func skipBlankLenient(src string, pos int) int {
+	for {
+		p := SkipBlank(src, pos)
+		if p < 0 || p >= len(src) || src[p] != '/' || p+1 >= len(src) {
+			return p
+		}
+		switch src[p+1] {
+		case '/':
+			i := strings.IndexByte(src[p+2:], '\n')
+			if i < 0 {
+				pos = len(src)
+			} else {
+				pos = p + 2 + i
+			}
+		case '*':
+			i := strings.Index(src[p+2:], "*/")
+			if i < 0 {
+				return -int(types.ERR_EOF)
+			}
+			pos = p + 2 + i + 2
+		default:
+			return p
+		}
+	}
+}
+
+// commentEnd returns the end offset of the comment starting at pos, or -1
+// if there is no comment there. It is used by PeekWithOptions when
+// ParserOptions.PreserveComments is set, so the comment can be reported as
+// a Comment token rather than being skipped by skipBlankLenient.
+func commentEnd(src string, pos int) int {
+	if pos+1 >= len(src) || src[pos] != '/' {
+		return -1
+	}
+	switch src[pos+1] {
+	case '/':
+		i := strings.IndexByte(src[pos+2:], '\n')
+		if i < 0 {
+			return len(src)
+		}
+		return pos + 2 + i
+	case '*':
+		i := strings.Index(src[pos+2:], "*/")
+		if i < 0 {
+			return -1
+		}
+		return pos + 2 + i + 2
+	}
+	return -1
+}
+
+// PeekWithOptions behaves like Peek, but on the lenient path also
+// recognizes single-quoted strings, unquoted identifier keys (reported as
+// String, matching how Peek reports quoted keys), trailing commas before a
+// closing bracket, and optionally comments. A nil or zero-value opts is
+// equivalent to calling Peek directly.
+func PeekWithOptions(src string, pos int, opts *ParserOptions) (ret int, next TokenState) {
+	if opts == nil || !opts.Lenient {
+		return Peek(src, pos)
+	}
+	if opts.PreserveComments {
+		if p := SkipBlank(src, pos); p >= 0 {
+			if commentEnd(src, p) >= 0 {
+				return p, Comment
+			}
+		}
+	}
+	pos = skipBlankLenient(src, pos)
+	if pos < 0 {
+		return pos, Invalid
+	}
+	switch c := src[pos]; c {
+	case '\'':
+		return pos, String
+	case ',':
+		if after := skipBlankLenient(src, pos+1); after >= 0 && after < len(src) && (src[after] == '}' || src[after] == ']') {
+			return PeekWithOptions(src, after, opts)
+		}
+		return pos, Comma
+	default:
+		// "true"/"false"/"null" and the "NaN"/"Infinity" numeric literals
+		// all satisfy isIdentStart too, but are reserved words, not bare
+		// identifier keys; let strict Peek classify them as it already
+		// would (True/False/Null), or fall through to the Number case
+		// added by DecodeValueWithOptions/SkipValueWithOptions.
+		switch {
+		case c == 't' && reservedWordAt(src, pos, "true"):
+			return Peek(src, pos)
+		case c == 'f' && reservedWordAt(src, pos, "false"):
+			return Peek(src, pos)
+		case c == 'n' && reservedWordAt(src, pos, "null"):
+			return Peek(src, pos)
+		case c == 'N' && reservedWordAt(src, pos, "NaN"):
+			return pos, Number
+		case c == 'I' && reservedWordAt(src, pos, "Infinity"):
+			return pos, Number
+		case isIdentStart(c):
+			return pos, String
+		default:
+			return Peek(src, pos)
+		}
+	}
+}
+
+// skipCommentValue returns the end offset of the comment at pos, for
+// callers that peeked a Comment token via PeekWithOptions and now want to
+// advance past it.
+func skipCommentValue(src string, pos int) (ret int) {
+	ret = commentEnd(src, pos)
+	if ret < 0 {
+		return -int(types.ERR_INVALID_CHAR)
+	}
+	return ret
+}
+
+// skipStringLenient behaves like skipString but also accepts single-quoted
+// strings, as used by JSON5. Which closing quote is required is determined
+// by the quote character actually present at pos.
+func skipStringLenient(src string, pos int) (ret int, ep int) {
+	if pos >= len(src) {
+		return -int(types.ERR_EOF), -1
+	}
+	if src[pos] == '"' {
+		return skipString(src, pos)
+	}
+	if src[pos] != '\'' {
+		return -int(types.ERR_INVALID_CHAR), -1
+	}
+
+	sp := pos + 1
+	ep = -1
+	for sp < len(src) {
+		c := src[sp]
+		if c == '\\' {
+			if ep == -1 {
+				ep = sp
+			}
+			sp += 2
+			continue
+		}
+		sp++
+		if c == '\'' {
+			return sp, ep
+		}
+	}
+	return -int(types.ERR_EOF), -1
+}
+
+// decodeStringLenient behaves like decodeString but dispatches to
+// skipStringLenient so it also accepts single-quoted strings.
+func decodeStringLenient(src string, pos int) (ret int, v string) {
+	ret, ep := skipStringLenient(src, pos)
+	if ret < 0 {
+		return ret, ""
+	}
+	if ep == -1 {
+		return ret, src[pos+1 : ret-1]
+	}
+	if src[pos] == '"' {
+		vv, ok := unquoteBytes(rt.Str2Mem(src[pos:ret]))
+		if !ok {
+			return -int(types.ERR_INVALID_CHAR), ""
+		}
+		return ret, string(vv)
+	}
+	vv, ok := unquoteBytes(requoteSingle(src[pos+1 : ret-1]))
+	if !ok {
+		return -int(types.ERR_INVALID_CHAR), ""
+	}
+	return ret, string(vv)
+}
+
+// requoteSingle converts the body of a single-quoted JSON5 string (with the
+// surrounding quotes already stripped) into a standard double-quoted
+// literal that unquoteBytes can parse: a bare "'" passes straight through
+// rather than needing "\\'", a bare '"' is legal and must be escaped so it
+// isn't mistaken for the closing quote, and existing backslash escapes are
+// preserved as-is, except "\\'", which has no meaning in a double-quoted
+// literal and is turned into a literal "'".
+func requoteSingle(body string) []byte {
+	out := make([]byte, 0, len(body)+2)
+	out = append(out, '"')
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			out = append(out, '\'')
+			i++
+		case c == '\\' && i+1 < len(body):
+			out = append(out, c, body[i+1])
+			i++
+		case c == '"':
+			out = append(out, '\\', '"')
+		default:
+			out = append(out, c)
+		}
+	}
+	out = append(out, '"')
+	return out
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hasPrefixAt(src string, pos int, prefix string) bool {
+	return pos+len(prefix) <= len(src) && src[pos:pos+len(prefix)] == prefix
+}
+
+// reservedWordAt reports whether the reserved word word occurs at pos in
+// src as a whole token rather than as the prefix of a longer unquoted
+// identifier key. "true"/"false"/"null"/"NaN"/"Infinity" all satisfy
+// isIdentStart too, so "truefoo", "nullable" and "falsey" must be told
+// apart from the literals they start with before either is decoded.
+func reservedWordAt(src string, pos int, word string) bool {
+	if !hasPrefixAt(src, pos, word) {
+		return false
+	}
+	end := pos + len(word)
+	return end >= len(src) || !(isIdentStart(src[end]) || isDigit(src[end]))
+}
+
+// hasInfinityAt reports whether an optionally-signed Infinity literal
+// starts at pos. decodeInt64 rejects such input with ERR_INVALID_CHAR
+// rather than the ERR_INVALID_NUMBER_FMT that triggers decodeInt64Lenient
+// and decodeFloat64Lenient's own int-then-float fallback, so callers must
+// check for it up front instead of relying on that fallback.
+func hasInfinityAt(src string, pos int) bool {
+	if pos < len(src) && (src[pos] == '-' || src[pos] == '+') {
+		pos++
+	}
+	return hasPrefixAt(src, pos, "Infinity")
+}
+
+// decodeInt64Lenient behaves like decodeInt64 but additionally accepts the
+// 0x/0X-prefixed hexadecimal integer literals permitted by JSON5.
+func decodeInt64Lenient(src string, pos int) (ret int, v int64, err error) {
+	neg, p := false, pos
+	if p < len(src) && src[p] == '-' {
+		neg, p = true, p+1
+	}
+	if !hasPrefixAt(src, p, "0x") && !hasPrefixAt(src, p, "0X") {
+		return decodeInt64(src, pos)
+	}
+	sp := p + 2
+	for sp < len(src) && isHexDigit(src[sp]) {
+		sp++
+	}
+	if sp == p+2 {
+		return -int(types.ERR_INVALID_CHAR), 0, nil
+	}
+	v, err = strconv.ParseInt(src[p+2:sp], 16, 64)
+	if err != nil {
+		return -int(types.ERR_INVALID_CHAR), 0, err
+	}
+	if neg {
+		v = -v
+	}
+	return sp, v, nil
+}
+
+// decodeFloat64Lenient behaves like decodeFloat64 but additionally accepts
+// the bare Infinity, -Infinity and NaN literals permitted by JSON5.
+func decodeFloat64Lenient(src string, pos int) (ret int, v float64, err error) {
+	if hasPrefixAt(src, pos, "NaN") {
+		return pos + 3, math.NaN(), nil
+	}
+	neg, p := false, pos
+	if p < len(src) && src[p] == '-' {
+		neg, p = true, p+1
+	}
+	if hasPrefixAt(src, p, "Infinity") {
+		v = math.Inf(1)
+		if neg {
+			v = math.Inf(-1)
+		}
+		return p + 8, v, nil
+	}
+	return decodeFloat64(src, pos)
+}
+
+// DecodeValueWithOptions behaves like DecodeValue, but on the lenient path
+// also accepts single-quoted strings and hex/Infinity/NaN numeric
+// literals. A nil or zero-value opts is equivalent to calling DecodeValue
+// directly.
+func DecodeValueWithOptions(src string, pos int, opts *ParserOptions) (ret int, v types.JsonState) {
+	if opts == nil || !opts.Lenient {
+		return DecodeValue(src, pos)
+	}
+	pos = skipBlankLenient(src, pos)
+	if pos < 0 {
+		return pos, types.JsonState{Vt: types.ValueType(pos)}
+	}
+	switch c := src[pos]; c {
+	case '\'':
+		var ep int
+		ret, ep = skipStringLenient(src, pos)
+		if ret < 0 {
+			return ret, types.JsonState{Vt: types.ValueType(ret)}
+		}
+		return ret, types.JsonState{Vt: types.V_STRING, Iv: int64(pos + 1), Ep: int64(ep)}
+	case 't':
+		if reservedWordAt(src, pos, "true") {
+			return DecodeValue(src, pos)
+		}
+		return decodeIdentKeyState(src, pos)
+	case 'f':
+		if reservedWordAt(src, pos, "false") {
+			return DecodeValue(src, pos)
+		}
+		return decodeIdentKeyState(src, pos)
+	case 'n':
+		if reservedWordAt(src, pos, "null") {
+			return DecodeValue(src, pos)
+		}
+		return decodeIdentKeyState(src, pos)
+	case 'N':
+		if reservedWordAt(src, pos, "NaN") {
+			ret, fv, _ := decodeFloat64Lenient(src, pos)
+			return ret, types.JsonState{Vt: types.V_DOUBLE, Dv: fv, Ep: int64(pos)}
+		}
+		return decodeIdentKeyState(src, pos)
+	case '-', '+', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		if hasInfinityAt(src, pos) {
+			ret, fv, _ := decodeFloat64Lenient(src, pos)
+			return ret, types.JsonState{Vt: types.V_DOUBLE, Dv: fv, Ep: int64(pos)}
+		}
+		ret, iv, _ := decodeInt64Lenient(src, pos)
+		if ret >= 0 {
+			return ret, types.JsonState{Vt: types.V_INTEGER, Iv: iv, Ep: int64(pos)}
+		}
+		if ret != -int(types.ERR_INVALID_NUMBER_FMT) {
+			return ret, types.JsonState{Vt: types.ValueType(ret)}
+		}
+		ret, fv, _ := decodeFloat64Lenient(src, pos)
+		if ret >= 0 {
+			return ret, types.JsonState{Vt: types.V_DOUBLE, Dv: fv, Ep: int64(pos)}
+		}
+		return ret, types.JsonState{Vt: types.ValueType(ret)}
+	case 'I':
+		if reservedWordAt(src, pos, "Infinity") {
+			ret, fv, _ := decodeFloat64Lenient(src, pos)
+			return ret, types.JsonState{Vt: types.V_DOUBLE, Dv: fv, Ep: int64(pos)}
+		}
+		return decodeIdentKeyState(src, pos)
+	}
+	if sret, sv := DecodeValue(src, pos); sret >= 0 {
+		return sret, sv
+	}
+	if isIdentStart(src[pos]) {
+		return decodeIdentKeyState(src, pos)
+	}
+	return DecodeValue(src, pos)
+}
+
+// decodeIdentKeyState decodes an unquoted identifier key at pos into the
+// JsonState DecodeValueWithOptions reports for it, e.g. when a "true"/
+// "false"/"null"/"NaN"/"Infinity"-looking token turns out to be a longer
+// identifier such as "truefoo". Identifier keys carry no surrounding
+// quotes, so unlike a quoted string's JsonState the content runs all the
+// way to ret (not ret-1), and Ep is always -1 since an identifier cannot
+// contain an escape sequence.
+func decodeIdentKeyState(src string, pos int) (ret int, v types.JsonState) {
+	if iret, _ := decodeIdentKey(src, pos); iret >= 0 {
+		return iret, types.JsonState{Vt: types.V_STRING, Iv: int64(pos), Ep: -1}
+	}
+	return DecodeValue(src, pos)
+}
+
+// SkipValueWithOptions behaves like SkipValue, but on the lenient path
+// also bypasses single-quoted strings, hex/Infinity/NaN numeric literals,
+// and unquoted identifier keys without decoding them. A nil or zero-value
+// opts is equivalent to calling SkipValue directly.
+func SkipValueWithOptions(src string, pos int, opts *ParserOptions) (ret int, start int) {
+	if opts == nil || !opts.Lenient {
+		return SkipValue(src, pos)
+	}
+	pos = skipBlankLenient(src, pos)
+	if pos < 0 {
+		return pos, -1
+	}
+
+	c := src[pos]
+	switch c {
+	case '\'':
+		ret, _ = skipStringLenient(src, pos)
+		return ret, pos
+	case 't':
+		if reservedWordAt(src, pos, "true") {
+			return SkipValue(src, pos)
+		}
+		return skipIdentKey(src, pos)
+	case 'f':
+		if reservedWordAt(src, pos, "false") {
+			return SkipValue(src, pos)
+		}
+		return skipIdentKey(src, pos)
+	case 'n':
+		if reservedWordAt(src, pos, "null") {
+			return SkipValue(src, pos)
+		}
+		return skipIdentKey(src, pos)
+	case 'N':
+		if reservedWordAt(src, pos, "NaN") {
+			return pos + 3, pos
+		}
+		return skipIdentKey(src, pos)
+	case 'I':
+		if reservedWordAt(src, pos, "Infinity") {
+			return pos + 8, pos
+		}
+		return skipIdentKey(src, pos)
+	case '-', '+', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		if hasInfinityAt(src, pos) {
+			if r, _, _ := decodeFloat64Lenient(src, pos); r >= 0 {
+				return r, pos
+			}
+		}
+		if r, _, _ := decodeInt64Lenient(src, pos); r >= 0 {
+			return r, pos
+		} else if types.ParsingError(-r) == types.ERR_INVALID_NUMBER_FMT {
+			if r2, _, _ := decodeFloat64Lenient(src, pos); r2 >= 0 {
+				return r2, pos
+			}
+		}
+		return SkipValue(src, pos)
+	}
+	if ret, start = SkipValue(src, pos); ret >= 0 {
+		return ret, start
+	}
+	if isIdentStart(c) {
+		return skipIdentKey(src, pos)
+	}
+	return ret, start
+}
+
+// skipIdentKey bypasses an unquoted identifier key at pos, e.g. when a
+// "true"/"false"/"null"/"NaN"/"Infinity"-looking token turns out to be a
+// longer identifier such as "truefoo", falling back to strict SkipValue if
+// pos does not start an identifier after all.
+func skipIdentKey(src string, pos int) (ret int, start int) {
+	if r, _ := decodeIdentKey(src, pos); r >= 0 {
+		return r, pos
+	}
+	return SkipValue(src, pos)
+}
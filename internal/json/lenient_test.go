@@ -0,0 +1,142 @@
+/**
+ * Copyright 2025 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"testing"
+
+	"github.com/cloudwego/dynamicgo/internal/types"
+)
+
+func TestPeekWithOptionsLenient(t *testing.T) {
+	opts := &ParserOptions{Lenient: true}
+	cases := []struct {
+		name string
+		src  string
+		want TokenState
+	}{
+		{"line comment before value", "// hi\n42", Number},
+		{"block comment before value", "/* hi */ true", True},
+		{"trailing comma before close brace", ",}", EndObj},
+		{"trailing comma before close bracket", ",]", EndArr},
+		{"single quoted string", "'a'", String},
+		{"unquoted identifier key", "foo", String},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ret, tok := PeekWithOptions(c.src, 0, opts)
+			if ret < 0 {
+				t.Fatalf("PeekWithOptions(%q) returned error code %d", c.src, ret)
+			}
+			if tok != c.want {
+				t.Fatalf("PeekWithOptions(%q) = %v, want %v", c.src, tok, c.want)
+			}
+		})
+	}
+}
+
+func TestPeekStrictRejectsComments(t *testing.T) {
+	if ret, _ := Peek("// hi\n42", 0); ret >= 0 {
+		t.Fatalf("strict Peek unexpectedly accepted a line comment")
+	}
+}
+
+func TestPeekWithOptionsReservedWordPrefixIsIdentKey(t *testing.T) {
+	opts := &ParserOptions{Lenient: true}
+	for _, src := range []string{"truefoo", "falsey", "nullable", "NaNable", "Infinitesimal"} {
+		t.Run(src, func(t *testing.T) {
+			ret, tok := PeekWithOptions(src, 0, opts)
+			if ret < 0 {
+				t.Fatalf("PeekWithOptions(%q) returned error code %d", src, ret)
+			}
+			if tok != String {
+				t.Fatalf("PeekWithOptions(%q) = %v, want String", src, tok)
+			}
+		})
+	}
+}
+
+func TestDecodeValueWithOptionsIdentKey(t *testing.T) {
+	src := "fooBar,"
+	ret, v := DecodeValueWithOptions(src, 0, &ParserOptions{Lenient: true})
+	if ret != 6 {
+		t.Fatalf("ret = %d, want 6", ret)
+	}
+	if v.Vt != types.V_STRING {
+		t.Fatalf("Vt = %v, want V_STRING", v.Vt)
+	}
+	if got := src[v.Iv:ret]; got != "fooBar" {
+		t.Fatalf("decoded ident key = %q, want %q", got, "fooBar")
+	}
+}
+
+func TestDecodeValueWithOptionsReservedWordPrefixIsIdentKey(t *testing.T) {
+	src := "truefoo,"
+	ret, v := DecodeValueWithOptions(src, 0, &ParserOptions{Lenient: true})
+	if ret != 7 {
+		t.Fatalf("ret = %d, want 7", ret)
+	}
+	if v.Vt != types.V_STRING {
+		t.Fatalf("Vt = %v, want V_STRING", v.Vt)
+	}
+	if got := src[v.Iv:ret]; got != "truefoo" {
+		t.Fatalf("decoded ident key = %q, want %q", got, "truefoo")
+	}
+}
+
+func TestDecodeValueWithOptionsNumericLiterals(t *testing.T) {
+	opts := &ParserOptions{Lenient: true}
+
+	if ret, v := DecodeValueWithOptions("0x1F", 0, opts); ret != 4 || v.Iv != 31 {
+		t.Fatalf("hex literal: ret=%d, Iv=%d, want ret=4, Iv=31", ret, v.Iv)
+	}
+	if ret, v := DecodeValueWithOptions("NaN", 0, opts); ret != 3 || v.Vt != types.V_DOUBLE {
+		t.Fatalf("NaN literal: ret=%d, Vt=%v", ret, v.Vt)
+	}
+	if ret, v := DecodeValueWithOptions("-Infinity", 0, opts); ret != 9 || v.Vt != types.V_DOUBLE {
+		t.Fatalf("-Infinity literal: ret=%d, Vt=%v", ret, v.Vt)
+	}
+}
+
+func TestDecodeStringLenientEscapeAndEmbeddedDoubleQuote(t *testing.T) {
+	src := `'a\n"b'`
+	ret, v := decodeStringLenient(src, 0)
+	if ret != len(src) {
+		t.Fatalf("ret = %d, want %d", ret, len(src))
+	}
+	if v != "a\n\"b" {
+		t.Fatalf("decodeStringLenient(%q) = %q, want %q", src, v, "a\n\"b")
+	}
+}
+
+func TestSkipValueWithOptionsLenient(t *testing.T) {
+	opts := &ParserOptions{Lenient: true}
+	cases := []struct {
+		src  string
+		want int
+	}{
+		{"'hello',", 7},
+		{"0x1F,", 4},
+		{"fooBar,", 6},
+		{"truefoo,", 7},
+	}
+	for _, c := range cases {
+		if ret, _ := SkipValueWithOptions(c.src, 0, opts); ret != c.want {
+			t.Errorf("SkipValueWithOptions(%q) = %d, want %d", c.src, ret, c.want)
+		}
+	}
+}
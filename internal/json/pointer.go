@@ -0,0 +1,615 @@
+/**
+ * Copyright 2025 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/dynamicgo/internal/types"
+)
+
+// ErrPointerNotFound is returned by Pointer.Get when no value exists at
+// the addressed path.
+var ErrPointerNotFound = errors.New("json: pointer not found")
+
+// Pointer is a compiled RFC 6901 JSON Pointer. It is evaluated against a
+// document by walking it with Peek/skipString/skipNumber/skipPair, so
+// addressing a value never requires decoding the sibling values it passes
+// over.
+type Pointer struct {
+	tokens []string
+}
+
+// ParsePointer compiles expr (e.g. "/a/b/0") into a Pointer. The empty
+// string addresses the whole document. RFC 6901 escapes ("~0" for "~" and
+// "~1" for "/") are decoded once, here, rather than on every Get.
+func ParsePointer(expr string) (Pointer, error) {
+	if expr == "" {
+		return Pointer{}, nil
+	}
+	if expr[0] != '/' {
+		return Pointer{}, errInvalidPointer
+	}
+	parts := strings.Split(expr[1:], "/")
+	for i, t := range parts {
+		if strings.IndexByte(t, '~') >= 0 {
+			t = strings.ReplaceAll(t, "~1", "/")
+			t = strings.ReplaceAll(t, "~0", "~")
+			parts[i] = t
+		}
+	}
+	return Pointer{tokens: parts}, nil
+}
+
+var errInvalidPointer = types.ParsingError(types.ERR_INVALID_CHAR)
+
+// Get returns the byte range [start, end) of the value addressed by p
+// within src, along with its value type. Sibling values along the path are
+// bypassed with SkipValue/skipString rather than being decoded.
+func (p Pointer) Get(src string) (start, end int, vt types.ValueType, err error) {
+	pos := 0
+	for _, tok := range p.tokens {
+		ret, tt := Peek(src, pos)
+		if ret < 0 {
+			return 0, 0, 0, types.ParsingError(-ret)
+		}
+		switch tt {
+		case Obj:
+			pos, err = findObjectMember(src, ret+1, tok)
+		case Arr:
+			pos, err = findArrayElement(src, ret+1, tok)
+		default:
+			err = errInvalidPointer
+		}
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	ret, v := DecodeValue(src, pos)
+	if ret < 0 {
+		return 0, 0, 0, types.ParsingError(-ret)
+	}
+	switch v.Vt {
+	case types.V_STRING:
+		return int(v.Iv) - 1, ret, v.Vt, nil
+	case types.V_OBJECT, types.V_ARRAY:
+		// DecodeValue only returns the position right after the opening
+		// '{'/'[' for these types; walk the body with SkipValue to get
+		// the true end of the sub-document.
+		end, _ := SkipValue(src, pos)
+		if end < 0 {
+			return 0, 0, 0, types.ParsingError(-end)
+		}
+		return pos, end, v.Vt, nil
+	}
+	return pos, ret, v.Vt, nil
+}
+
+// findObjectMember scans the object whose '{' was at pos-1 (pos is the
+// first byte after it) for a member named key, and returns the start
+// offset of its value. Members that don't match are bypassed with
+// skipString/SkipValue without being decoded.
+func findObjectMember(src string, pos int, key string) (int, error) {
+	for {
+		ret, tok := Peek(src, pos)
+		if ret < 0 {
+			return 0, types.ParsingError(-ret)
+		}
+		if tok == EndObj {
+			return 0, ErrPointerNotFound
+		}
+		kend, ep := skipString(src, ret)
+		if kend < 0 {
+			return 0, types.ParsingError(-kend)
+		}
+		var k string
+		if ep == -1 {
+			k = src[ret+1 : kend-1]
+		} else {
+			vv, ok := unquoteBytes([]byte(src[ret:kend]))
+			if !ok {
+				return 0, errInvalidPointer
+			}
+			k = string(vv)
+		}
+
+		cret, ctok := Peek(src, kend)
+		if cret < 0 || ctok != Colon {
+			return 0, errInvalidPointer
+		}
+		vstart, _ := Peek(src, cret+1)
+		if vstart < 0 {
+			return 0, types.ParsingError(-vstart)
+		}
+		if k == key {
+			return vstart, nil
+		}
+		vend, _ := SkipValue(src, vstart)
+		if vend < 0 {
+			return 0, types.ParsingError(-vend)
+		}
+		nret, ntok := Peek(src, vend)
+		if nret < 0 {
+			return 0, types.ParsingError(-nret)
+		}
+		switch ntok {
+		case Comma:
+			pos = nret + 1
+		case EndObj:
+			return 0, ErrPointerNotFound
+		default:
+			return 0, errInvalidPointer
+		}
+	}
+}
+
+// findArrayElement scans the array whose '[' was at pos-1 for element
+// index idx (parsed from the RFC 6901 "-" or decimal token), bypassing
+// earlier elements with SkipValue.
+func findArrayElement(src string, pos int, idxTok string) (int, error) {
+	if idxTok == "-" {
+		return 0, ErrPointerNotFound
+	}
+	idx, err := strconv.Atoi(idxTok)
+	if err != nil || idx < 0 {
+		return 0, errInvalidPointer
+	}
+	for i := 0; ; i++ {
+		ret, tok := Peek(src, pos)
+		if ret < 0 {
+			return 0, types.ParsingError(-ret)
+		}
+		if tok == EndArr {
+			return 0, ErrPointerNotFound
+		}
+		if i == idx {
+			return ret, nil
+		}
+		vend, _ := SkipValue(src, ret)
+		if vend < 0 {
+			return 0, types.ParsingError(-vend)
+		}
+		nret, ntok := Peek(src, vend)
+		if nret < 0 {
+			return 0, types.ParsingError(-nret)
+		}
+		switch ntok {
+		case Comma:
+			pos = nret + 1
+		case EndArr:
+			return 0, ErrPointerNotFound
+		default:
+			return 0, errInvalidPointer
+		}
+	}
+}
+
+// Segment identifies one step of a matched Path: either an object member
+// (Key set) or an array element (Key empty, Index set).
+type Segment struct {
+	Key   string
+	Index int
+}
+
+type pathOpKind int8
+
+const (
+	pathChild pathOpKind = iota
+	pathIndex
+	pathSlice
+	pathWildcard
+	pathRecursive
+)
+
+type pathOp struct {
+	kind               pathOpKind
+	key                string
+	index              int
+	sliceFrom, sliceTo int
+	hasFrom, hasTo     bool
+}
+
+// Path is a compiled JSONPath-like expression supporting dotted child
+// access, bracketed indices and slices, the "*" wildcard, and ".."
+// recursive descent.
+type Path struct {
+	ops []pathOp
+}
+
+// ParsePath compiles expr (e.g. "$.a.b[*].c..d[1:3]") into a Path.
+func ParsePath(expr string) (Path, error) {
+	expr = strings.TrimPrefix(expr, "$")
+	var ops []pathOp
+	i := 0
+	for i < len(expr) {
+		switch {
+		case strings.HasPrefix(expr[i:], ".."):
+			ops = append(ops, pathOp{kind: pathRecursive})
+			i += 2
+		case expr[i] == '.':
+			i++
+		case expr[i] == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return Path{}, errInvalidPointer
+			}
+			inner := expr[i+1 : i+end]
+			i += end + 1
+			op, err := parseBracket(inner)
+			if err != nil {
+				return Path{}, err
+			}
+			ops = append(ops, op)
+			continue
+		default:
+			end := i
+			for end < len(expr) && expr[end] != '.' && expr[end] != '[' {
+				end++
+			}
+			tok := expr[i:end]
+			i = end
+			if tok == "*" {
+				ops = append(ops, pathOp{kind: pathWildcard})
+			} else {
+				ops = append(ops, pathOp{kind: pathChild, key: tok})
+			}
+		}
+	}
+	return Path{ops: ops}, nil
+}
+
+func parseBracket(inner string) (pathOp, error) {
+	if inner == "*" {
+		return pathOp{kind: pathWildcard}, nil
+	}
+	if strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, "\"") {
+		return pathOp{kind: pathChild, key: inner[1 : len(inner)-1]}, nil
+	}
+	if strings.Contains(inner, ":") {
+		parts := strings.SplitN(inner, ":", 2)
+		op := pathOp{kind: pathSlice}
+		if parts[0] != "" {
+			n, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return pathOp{}, errInvalidPointer
+			}
+			op.sliceFrom, op.hasFrom = n, true
+		}
+		if parts[1] != "" {
+			n, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return pathOp{}, errInvalidPointer
+			}
+			op.sliceTo, op.hasTo = n, true
+		}
+		return op, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return pathOp{}, errInvalidPointer
+	}
+	return pathOp{kind: pathIndex, index: n}, nil
+}
+
+// ForEach walks src and invokes cb for every value matched by p, in
+// document order, except under ".." recursive descent, where the matches
+// found at a level are reported before those found by descending into its
+// children. path is the sequence of Segments leading to the match,
+// reused across calls, so cb must not retain it. Returning false from cb
+// stops the walk early.
+func (p Path) ForEach(src string, cb func(path []Segment, start, end int) bool) error {
+	stop := false
+	_, err := walkPath(src, 0, p.ops, nil, cb, &stop)
+	return err
+}
+
+func walkPath(src string, pos int, ops []pathOp, path []Segment, cb func([]Segment, int, int) bool, stop *bool) (int, error) {
+	if *stop {
+		return pos, nil
+	}
+	if len(ops) == 0 {
+		end, _ := SkipValue(src, pos)
+		if end < 0 {
+			return 0, types.ParsingError(-end)
+		}
+		if !cb(path, pos, end) {
+			*stop = true
+		}
+		return end, nil
+	}
+
+	op := ops[0]
+	if op.kind == pathRecursive {
+		// Recursive descent: try matching the remaining ops at every
+		// level, then additionally recurse into every child regardless
+		// of match. A level the next op cannot select from (a scalar, an
+		// array for a key, an object for an index or slice) is skipped.
+		rest := ops[1:]
+		ret, tok := Peek(src, pos)
+		if ret < 0 {
+			return 0, types.ParsingError(-ret)
+		}
+		if len(rest) == 0 || rest[0].kind == pathRecursive ||
+			(tok == Obj && (rest[0].kind == pathChild || rest[0].kind == pathWildcard)) ||
+			(tok == Arr && rest[0].kind != pathChild) {
+			if _, err := walkPath(src, pos, rest, path, cb, stop); err != nil {
+				return 0, err
+			}
+		}
+		return walkChildren(src, pos, func(seg Segment, start int) error {
+			_, err := walkPath(src, start, ops, append(path, seg), cb, stop)
+			return err
+		})
+	}
+
+	ret, tok := Peek(src, pos)
+	if ret < 0 {
+		return 0, types.ParsingError(-ret)
+	}
+	switch tok {
+	case Obj:
+		return walkObjectOp(src, ret+1, op, ops, path, cb, stop)
+	case Arr:
+		return walkArrayOp(src, ret+1, op, ops, path, cb, stop)
+	default:
+		return 0, errInvalidPointer
+	}
+}
+
+// walkChildren visits every member/element of the object or array value
+// starting at pos, calling visit(segment, valueStart) for each.
+func walkChildren(src string, pos int, visit func(Segment, int) error) (int, error) {
+	ret, tok := Peek(src, pos)
+	if ret < 0 {
+		return 0, types.ParsingError(-ret)
+	}
+	switch tok {
+	case Obj:
+		p := ret + 1
+		for {
+			r, t := Peek(src, p)
+			if r < 0 {
+				return 0, types.ParsingError(-r)
+			}
+			if t == EndObj {
+				return r + 1, nil
+			}
+			kend, ep := skipString(src, r)
+			if kend < 0 {
+				return 0, types.ParsingError(-kend)
+			}
+			key := decodeKeyRaw(src, r, kend, ep)
+			cret, ctok := Peek(src, kend)
+			if cret < 0 {
+				return 0, types.ParsingError(-cret)
+			}
+			if ctok != Colon {
+				return 0, errInvalidPointer
+			}
+			vstart, _ := Peek(src, cret+1)
+			if vstart < 0 {
+				return 0, types.ParsingError(-vstart)
+			}
+			if err := visit(Segment{Key: key}, vstart); err != nil {
+				return 0, err
+			}
+			vend, _ := SkipValue(src, vstart)
+			if vend < 0 {
+				return 0, types.ParsingError(-vend)
+			}
+			nret, ntok := Peek(src, vend)
+			if ntok == EndObj {
+				return nret + 1, nil
+			}
+			p = nret + 1
+		}
+	case Arr:
+		p := ret + 1
+		for idx := 0; ; idx++ {
+			r, t := Peek(src, p)
+			if r < 0 {
+				return 0, types.ParsingError(-r)
+			}
+			if t == EndArr {
+				return r + 1, nil
+			}
+			if err := visit(Segment{Index: idx}, r); err != nil {
+				return 0, err
+			}
+			vend, _ := SkipValue(src, r)
+			if vend < 0 {
+				return 0, types.ParsingError(-vend)
+			}
+			nret, ntok := Peek(src, vend)
+			if ntok == EndArr {
+				return nret + 1, nil
+			}
+			p = nret + 1
+		}
+	default:
+		// Scalars have no children; nothing to visit.
+		ret2, _ := SkipValue(src, pos)
+		return ret2, nil
+	}
+}
+
+func decodeKeyRaw(src string, start, end, ep int) string {
+	if ep == -1 {
+		return src[start+1 : end-1]
+	}
+	vv, ok := unquoteBytes([]byte(src[start:end]))
+	if !ok {
+		return src[start+1 : end-1]
+	}
+	return string(vv)
+}
+
+func walkObjectOp(src string, pos int, op pathOp, ops []pathOp, path []Segment, cb func([]Segment, int, int) bool, stop *bool) (int, error) {
+	switch op.kind {
+	case pathChild:
+		p := pos
+		for {
+			r, t := Peek(src, p)
+			if r < 0 {
+				return 0, types.ParsingError(-r)
+			}
+			if t == EndObj {
+				return r + 1, nil
+			}
+			kend, ep := skipString(src, r)
+			if kend < 0 {
+				return 0, types.ParsingError(-kend)
+			}
+			key := decodeKeyRaw(src, r, kend, ep)
+			cret, ctok := Peek(src, kend)
+			if cret < 0 {
+				return 0, types.ParsingError(-cret)
+			}
+			if ctok != Colon {
+				return 0, errInvalidPointer
+			}
+			vstart, _ := Peek(src, cret+1)
+			if vstart < 0 {
+				return 0, types.ParsingError(-vstart)
+			}
+			if key == op.key {
+				end, err := walkPath(src, vstart, ops[1:], append(path, Segment{Key: key}), cb, stop)
+				if err != nil {
+					return 0, err
+				}
+				nret, ntok := Peek(src, end)
+				if nret < 0 {
+					return 0, types.ParsingError(-nret)
+				}
+				switch ntok {
+				case EndObj:
+					return nret + 1, nil
+				case Comma:
+					// Skip the remaining members so the caller gets the
+					// position past this object's closing '}'.
+					oend, _ := SkipValue(src, pos-1)
+					if oend < 0 {
+						return 0, types.ParsingError(-oend)
+					}
+					return oend, nil
+				default:
+					return 0, errInvalidPointer
+				}
+			}
+			vend, _ := SkipValue(src, vstart)
+			if vend < 0 {
+				return 0, types.ParsingError(-vend)
+			}
+			nret, ntok := Peek(src, vend)
+			if ntok == EndObj {
+				return nret + 1, nil
+			}
+			p = nret + 1
+		}
+	case pathWildcard:
+		return walkChildren(src, pos-1, func(seg Segment, start int) error {
+			_, err := walkPath(src, start, ops[1:], append(path, seg), cb, stop)
+			return err
+		})
+	default:
+		return 0, errInvalidPointer
+	}
+}
+
+func walkArrayOp(src string, pos int, op pathOp, ops []pathOp, path []Segment, cb func([]Segment, int, int) bool, stop *bool) (int, error) {
+	switch op.kind {
+	case pathIndex:
+		open := pos - 1
+		for idx := 0; ; idx++ {
+			r, t := Peek(src, pos)
+			if r < 0 {
+				return 0, types.ParsingError(-r)
+			}
+			if t == EndArr {
+				return r + 1, nil
+			}
+			if idx == op.index {
+				end, err := walkPath(src, r, ops[1:], append(path, Segment{Index: idx}), cb, stop)
+				if err != nil {
+					return 0, err
+				}
+				nret, ntok := Peek(src, end)
+				if nret < 0 {
+					return 0, types.ParsingError(-nret)
+				}
+				switch ntok {
+				case EndArr:
+					return nret + 1, nil
+				case Comma:
+					// Skip the remaining elements so the caller gets the
+					// position past this array's closing ']'.
+					aend, _ := SkipValue(src, open)
+					if aend < 0 {
+						return 0, types.ParsingError(-aend)
+					}
+					return aend, nil
+				default:
+					return 0, errInvalidPointer
+				}
+			}
+			vend, _ := SkipValue(src, r)
+			if vend < 0 {
+				return 0, types.ParsingError(-vend)
+			}
+			nret, ntok := Peek(src, vend)
+			if ntok == EndArr {
+				return nret + 1, nil
+			}
+			pos = nret + 1
+		}
+	case pathSlice:
+		idx := 0
+		for {
+			r, t := Peek(src, pos)
+			if r < 0 {
+				return 0, types.ParsingError(-r)
+			}
+			if t == EndArr {
+				return r + 1, nil
+			}
+			if (!op.hasFrom || idx >= op.sliceFrom) && (!op.hasTo || idx < op.sliceTo) {
+				if _, err := walkPath(src, r, ops[1:], append(path, Segment{Index: idx}), cb, stop); err != nil {
+					return 0, err
+				}
+			}
+			vend, _ := SkipValue(src, r)
+			if vend < 0 {
+				return 0, types.ParsingError(-vend)
+			}
+			nret, ntok := Peek(src, vend)
+			if ntok == EndArr {
+				return nret + 1, nil
+			}
+			pos = nret + 1
+			idx++
+		}
+	case pathWildcard:
+		return walkChildren(src, pos-1, func(seg Segment, start int) error {
+			_, err := walkPath(src, start, ops[1:], append(path, seg), cb, stop)
+			return err
+		})
+	default:
+		return 0, errInvalidPointer
+	}
+}
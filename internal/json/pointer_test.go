@@ -0,0 +1,162 @@
+/**
+ * Copyright 2025 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import "testing"
+
+func TestPointerGet(t *testing.T) {
+	src := `{"a":{"b":[1,2,3]},"c":"hi"}`
+
+	p, err := ParsePointer("/a/b/1")
+	if err != nil {
+		t.Fatalf("ParsePointer error: %v", err)
+	}
+	start, end, _, err := p.Get(src)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if got := src[start:end]; got != "2" {
+		t.Fatalf("Get() = %q, want %q", got, "2")
+	}
+}
+
+func TestPointerGetEscapedSegment(t *testing.T) {
+	src := `{"a/b":{"c~d":1}}`
+	p, err := ParsePointer("/a~1b/c~0d")
+	if err != nil {
+		t.Fatalf("ParsePointer error: %v", err)
+	}
+	start, end, _, err := p.Get(src)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if got := src[start:end]; got != "1" {
+		t.Fatalf("Get() = %q, want %q", got, "1")
+	}
+}
+
+func TestPointerGetObjectValue(t *testing.T) {
+	src := `{"a":{"b":1},"c":2}`
+	p, err := ParsePointer("/a")
+	if err != nil {
+		t.Fatalf("ParsePointer error: %v", err)
+	}
+	start, end, _, err := p.Get(src)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if got := src[start:end]; got != `{"b":1}` {
+		t.Fatalf("Get() = %q, want %q", got, `{"b":1}`)
+	}
+}
+
+func TestPointerGetWholeDocumentObject(t *testing.T) {
+	src := `{"a":1}`
+	p, err := ParsePointer("")
+	if err != nil {
+		t.Fatalf("ParsePointer error: %v", err)
+	}
+	start, end, _, err := p.Get(src)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if got := src[start:end]; got != src {
+		t.Fatalf("Get() = %q, want %q", got, src)
+	}
+}
+
+func TestPointerGetNotFound(t *testing.T) {
+	p, err := ParsePointer("/missing")
+	if err != nil {
+		t.Fatalf("ParsePointer error: %v", err)
+	}
+	if _, _, _, err := p.Get(`{"a":1}`); err != ErrPointerNotFound {
+		t.Fatalf("Get() error = %v, want ErrPointerNotFound", err)
+	}
+}
+
+func TestPathForEachWildcard(t *testing.T) {
+	src := `{"items":[{"v":1},{"v":2},{"v":3}]}`
+	path, err := ParsePath("$.items[*].v")
+	if err != nil {
+		t.Fatalf("ParsePath error: %v", err)
+	}
+
+	var got []string
+	err = path.ForEach(src, func(segs []Segment, start, end int) bool {
+		got = append(got, src[start:end])
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ForEach error: %v", err)
+	}
+	want := []string{"1", "2", "3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("match %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPathForEachObjectChildRejectsMalformedTail(t *testing.T) {
+	path, err := ParsePath("$.a")
+	if err != nil {
+		t.Fatalf("ParsePath error: %v", err)
+	}
+	// After the matched "a" value, the document closes with ']' instead of
+	// ',' or '}' - walkObjectOp must reject this rather than returning a
+	// bogus "next position" past it.
+	if err := path.ForEach(`{"a":1]}`, func([]Segment, int, int) bool { return true }); err == nil {
+		t.Fatalf("expected an error walking a malformed object tail, got nil")
+	}
+}
+
+func TestPathForEachArrayIndexRejectsMalformedTail(t *testing.T) {
+	path, err := ParsePath("$[0]")
+	if err != nil {
+		t.Fatalf("ParsePath error: %v", err)
+	}
+	// After the matched index-0 value, the document closes with '}' instead
+	// of ',' or ']' - walkArrayOp must reject this rather than returning a
+	// bogus "next position" past it.
+	if err := path.ForEach(`[1}`, func([]Segment, int, int) bool { return true }); err == nil {
+		t.Fatalf("expected an error walking a malformed array tail, got nil")
+	}
+}
+
+func TestPathForEachTruncatedObjectDoesNotPanic(t *testing.T) {
+	path, err := ParsePath("$.a")
+	if err != nil {
+		t.Fatalf("ParsePath error: %v", err)
+	}
+	if err := path.ForEach(`{"a"`, func([]Segment, int, int) bool { return true }); err == nil {
+		t.Fatalf("expected an error walking a truncated object, got nil")
+	}
+}
+
+func TestPathForEachWildcardTruncatedObjectDoesNotPanic(t *testing.T) {
+	path, err := ParsePath("$.*")
+	if err != nil {
+		t.Fatalf("ParsePath error: %v", err)
+	}
+	if err := path.ForEach(`{"a"`, func([]Segment, int, int) bool { return true }); err == nil {
+		t.Fatalf("expected an error walking a truncated object, got nil")
+	}
+}
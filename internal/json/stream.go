@@ -0,0 +1,348 @@
+/**
+ * Copyright 2025 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"io"
+
+	"github.com/cloudwego/dynamicgo/internal/rt"
+	"github.com/cloudwego/dynamicgo/internal/types"
+)
+
+// StreamParser is a pull-based tokenizer over an io.Reader. Unlike Parser,
+// which requires the whole document to be addressable as a single string,
+// StreamParser keeps only bufCap bytes buffered between tokens, so it can
+// consume arbitrarily large documents (multi-GB logs, chunked HTTP bodies)
+// without materializing them as a whole.
+//
+// That bound only holds between tokens, not within one: a single String or
+// Number is always read as a contiguous buffer window, so Next and the
+// typed accessors below grow buf past bufCap, without limit, to fit
+// whichever token is currently being read. WriteTo is no exception: it
+// also assembles the whole token in buf before writing it out, so for a
+// single huge token it needs as much memory as Bytes; it only saves the
+// caller the copy into a Go string that StringValue makes.
+//
+// It exposes the same token vocabulary as Peek/DecodeValue by reusing
+// skipString, skipNumber, decodeInt64 and decodeFloat64 against a string
+// view of its internal buffer window; the in-memory Parser fast path is
+// untouched by this file.
+type StreamParser struct {
+	r   io.Reader
+	buf []byte
+	pos int // start of unconsumed data, and of the most recently peeked token
+	end int // end of valid data in buf
+	cap int // target buffer size; buf may grow past this for one oversized token
+
+	tok    TokenState
+	tokEnd int // end of the current token within buf, valid after Next
+	eof    bool
+}
+
+// NewStreamParser wraps r in a StreamParser that keeps roughly bufCap bytes
+// buffered at a time, growing the buffer only when a single token (a long
+// string or number) does not fit in it.
+func NewStreamParser(r io.Reader, bufCap int) *StreamParser {
+	if bufCap <= 0 {
+		bufCap = 4096
+	}
+	return &StreamParser{r: r, buf: make([]byte, 0, bufCap), cap: bufCap}
+}
+
+// window returns a string view of the unconsumed buffered bytes. It aliases
+// p.buf, so it must not be retained past the next call that mutates buf.
+func (p *StreamParser) window() string {
+	return rt.Mem2Str(p.buf[p.pos:p.end])
+}
+
+// compact drops already-consumed bytes from the front of buf so refill has
+// room to read more without growing indefinitely.
+func (p *StreamParser) compact() {
+	if p.pos == 0 {
+		return
+	}
+	n := copy(p.buf[:cap(p.buf)], p.buf[p.pos:p.end])
+	p.buf = p.buf[:n]
+	p.end = n
+	p.pos = 0
+}
+
+// refill ensures at least need bytes are available in the window, reading
+// more from r and growing buf past p.cap if a single token requires it, and
+// shrinking it back to p.cap once such a token has been consumed. It
+// returns io.EOF only once the underlying reader is exhausted and fewer
+// than need bytes remain.
+func (p *StreamParser) refill(need int) error {
+	if n := p.end - p.pos; cap(p.buf) > p.cap && need <= p.cap && n <= p.cap {
+		shrunk := make([]byte, n, p.cap)
+		copy(shrunk, p.buf[p.pos:p.end])
+		p.buf, p.pos, p.end = shrunk, 0, n
+	}
+	p.compact()
+	for p.end-p.pos < need {
+		if p.eof {
+			return io.EOF
+		}
+		if free := cap(p.buf) - p.end; free == 0 {
+			grown := make([]byte, p.end, growCap(cap(p.buf), p.end+need))
+			copy(grown, p.buf)
+			p.buf = grown
+		}
+		n, err := p.r.Read(p.buf[p.end:cap(p.buf)])
+		p.end += n
+		p.buf = p.buf[:p.end]
+		if err != nil {
+			if err == io.EOF {
+				p.eof = true
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func growCap(cur, want int) int {
+	for cur < want {
+		cur *= 2
+	}
+	return cur
+}
+
+// Next advances past the previously returned token (if any) and peeks the
+// next one, growing the buffer window as needed for tokens that straddle
+// it. It returns io.EOF once the stream is exhausted with no token left.
+//
+// Next always resolves tokEnd to the token's full extent, using skipString/
+// skipNumber for String/Number so that calling Next again without reading
+// the value (a normal "walk the structure, decode only what I need" usage)
+// advances past it instead of re-peeking the same token forever. The typed
+// accessors below re-decode from the same window to produce a value (Iv,
+// Dv, the unescaped string, ...); they don't need to move tokEnd further,
+// since Next already did.
+func (p *StreamParser) Next() (TokenState, error) {
+	p.pos += p.tokEnd
+	p.tokEnd = 0
+
+	need := 1
+	for {
+		if err := p.refill(need); err != nil {
+			if err == io.EOF && p.end == p.pos {
+				return Invalid, io.EOF
+			}
+			if err != io.EOF {
+				return Invalid, err
+			}
+		}
+		w := p.window()
+		ret, tok := Peek(w, 0)
+		if ret < 0 {
+			if types.ParsingError(-ret) == types.ERR_EOF && p.eof {
+				// Only blanks are left before the end of the stream.
+				return Invalid, io.EOF
+			}
+			if types.ParsingError(-ret) != types.ERR_EOF {
+				return Invalid, errFromCode(ret)
+			}
+			need = len(w) + 1
+			continue
+		}
+
+		end := ret
+		switch tok {
+		case Obj, Arr, EndObj, EndArr, Comma, Colon:
+			end = ret + 1
+		case Null:
+			end = decodeNull(w, ret)
+		case True:
+			end = decodeTrue(w, ret)
+		case False:
+			end = decodeFalse(w, ret)
+		case String:
+			end, _ = skipString(w, ret)
+			if end >= 0 && end == len(w) && !p.eof {
+				// skipString also stops at the end of the window when it
+				// finds no closing quote, so the string may be cut short.
+				need = len(w) + 1
+				continue
+			}
+		case Number:
+			end = skipNumber(w, ret)
+			if end >= 0 && end == len(w) && !p.eof {
+				// A Number has no closing delimiter, so reaching the end
+				// of the window doesn't prove the number is complete - it
+				// may just be where the buffer ran out.
+				need = len(w) + 1
+				continue
+			}
+		}
+		if end < 0 {
+			if types.ParsingError(-end) != types.ERR_EOF || p.eof {
+				return Invalid, errFromCode(end)
+			}
+			need = len(w) + 1
+			continue
+		}
+
+		// Move pos past any leading blanks so it marks the token itself.
+		p.pos += ret
+		p.tok = tok
+		p.tokEnd = end - ret
+		return tok, nil
+	}
+}
+
+// value re-decodes the current token from the start of the window and
+// returns its end offset and JsonState, growing the window as needed. The
+// typed accessors below call it and then set tokEnd to the returned end.
+//
+// A Number has no closing delimiter of its own, so DecodeValue reports it
+// as complete the instant the window runs out of digits - even when that
+// is only because the window itself ends there and more digits are still
+// sitting unread in r. Unlike a String's closing quote, that makes a
+// Number's end ambiguous until either a non-digit byte follows it in the
+// window or the stream is known to be exhausted, so value() keeps growing
+// and re-decoding in that case rather than trusting the first result.
+func (p *StreamParser) value() (int, types.JsonState, error) {
+	need := p.tokEnd + 1
+	for {
+		if err := p.refill(need); err != nil && err != io.EOF {
+			return 0, types.JsonState{}, err
+		}
+		w := p.window()
+		ret, v := DecodeValue(w, 0)
+		if ret >= 0 {
+			if (v.Vt == types.V_INTEGER || v.Vt == types.V_DOUBLE) && ret == len(w) && !p.eof {
+				need = len(w) + 1
+				continue
+			}
+			return ret, v, nil
+		}
+		if types.ParsingError(-ret) != types.ERR_EOF || p.eof {
+			return 0, types.JsonState{}, errFromCode(ret)
+		}
+		need = len(w) + 1
+	}
+}
+
+// Int64 decodes the current Number token as an int64. It returns an error
+// if the current token is not a Number.
+func (p *StreamParser) Int64() (int64, error) {
+	end, v, err := p.value()
+	if err != nil {
+		return 0, err
+	}
+	if v.Vt != types.V_INTEGER {
+		return 0, errFromCode(-int(types.ERR_INVALID_CHAR))
+	}
+	p.tokEnd = end
+	return v.Iv, nil
+}
+
+// Float64 decodes the current Number token as a float64. It returns an
+// error if the current token is not a Number.
+func (p *StreamParser) Float64() (float64, error) {
+	end, v, err := p.value()
+	if err != nil {
+		return 0, err
+	}
+	if v.Vt != types.V_INTEGER && v.Vt != types.V_DOUBLE {
+		return 0, errFromCode(-int(types.ERR_INVALID_CHAR))
+	}
+	p.tokEnd = end
+	if v.Vt == types.V_INTEGER {
+		return float64(v.Iv), nil
+	}
+	return v.Dv, nil
+}
+
+// StringValue decodes the current String token, unescaping it. The whole
+// token is held in the buffer while it is decoded.
+func (p *StreamParser) StringValue() (string, error) {
+	end, v, err := p.value()
+	if err != nil {
+		return "", err
+	}
+	p.tokEnd = end
+	w := p.window()
+	// Mirrors decodeString in decode.go: Ep is -1 when the string has no
+	// escapes at all, in which case the content needs no unquoting.
+	// Unlike decodeString's src, w aliases p.buf, which Next/refill/
+	// compact go on to overwrite in place, so the result must be copied
+	// out rather than sliced directly - string([]byte(...)) forces that
+	// copy the same way the escaped branch below already does via
+	// unquoteBytes.
+	if v.Ep == -1 {
+		return string([]byte(w[v.Iv : end-1])), nil
+	}
+	vv, ok := unquoteBytes([]byte(w[v.Iv-1 : end]))
+	if !ok {
+		return "", errFromCode(-int(types.ERR_INVALID_CHAR))
+	}
+	return string(vv), nil
+}
+
+// Bytes returns the raw, still-escaped bytes of the current token. The
+// slice aliases the internal buffer and is only valid until the next call
+// that advances the parser.
+func (p *StreamParser) Bytes() []byte {
+	end, _, err := p.value()
+	if err != nil {
+		return nil
+	}
+	p.tokEnd = end
+	return p.buf[p.pos : p.pos+end]
+}
+
+// WriteTo writes the raw, still-escaped bytes of the current token to w.
+// The whole token is first assembled in the internal buffer, growing it as
+// needed, and then written with a single Write; it is not forwarded in
+// chunks, so it needs as much memory as Bytes for the same token.
+func (p *StreamParser) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	need := p.tokEnd + 1
+	for {
+		if err := p.refill(need); err != nil && err != io.EOF {
+			return written, err
+		}
+		sv := p.window()
+		ret, _ := SkipValue(sv, 0)
+		if ret >= 0 {
+			// A Number has no closing delimiter, so (as in value()) ret
+			// reaching the end of the window doesn't prove the number is
+			// complete - it may just be where the buffer ran out.
+			if p.tok == Number && ret == len(sv) && !p.eof {
+				need = len(sv) + 1
+				continue
+			}
+			n, err := w.Write(p.buf[p.pos : p.pos+ret])
+			written += int64(n)
+			p.tokEnd = ret
+			return written, err
+		}
+		if types.ParsingError(-ret) != types.ERR_EOF || p.eof {
+			return written, errFromCode(ret)
+		}
+		// The token runs past the end of the window: grow the window and
+		// try again. Nothing is written until the whole token is buffered.
+		need = len(sv) + 1
+	}
+}
+
+func errFromCode(ret int) error {
+	return types.ParsingError(-ret)
+}
@@ -0,0 +1,171 @@
+/**
+ * Copyright 2025 ByteDance Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamParserNextWalksArray(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader("[1,2]"), 16)
+
+	want := []TokenState{Arr, Number, Comma, Number, EndArr}
+	for i, w := range want {
+		tok, err := sp.Next()
+		if err != nil {
+			t.Fatalf("token %d: Next() error: %v", i, err)
+		}
+		if tok != w {
+			t.Fatalf("token %d = %v, want %v", i, tok, w)
+		}
+		if tok == Number {
+			if _, err := sp.Int64(); err != nil {
+				t.Fatalf("token %d: Int64() error: %v", i, err)
+			}
+		}
+	}
+	if _, err := sp.Next(); err == nil {
+		t.Fatalf("expected an error (io.EOF) once the document is exhausted")
+	}
+}
+
+func TestStreamParserNextSmallBuffer(t *testing.T) {
+	// bufCap smaller than the document forces refill/grow on every Next.
+	sp := NewStreamParser(strings.NewReader(`{"a":12345,"b":[true,false,null]}`), 1)
+
+	want := []TokenState{Obj, String, Colon, Number, Comma, String, Colon, Arr, True, Comma, False, Comma, Null, EndArr, EndObj}
+	for i, w := range want {
+		tok, err := sp.Next()
+		if err != nil {
+			t.Fatalf("token %d: Next() error: %v", i, err)
+		}
+		if tok != w {
+			t.Fatalf("token %d = %v, want %v", i, tok, w)
+		}
+		switch tok {
+		case String:
+			if _, err := sp.StringValue(); err != nil {
+				t.Fatalf("token %d: StringValue() error: %v", i, err)
+			}
+		case Number:
+			if _, err := sp.Int64(); err != nil {
+				t.Fatalf("token %d: Int64() error: %v", i, err)
+			}
+		}
+	}
+}
+
+func TestStreamParserStringValueNoEscape(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader(`"hello"`), 16)
+	if tok, err := sp.Next(); err != nil || tok != String {
+		t.Fatalf("Next() = %v, %v, want String, nil", tok, err)
+	}
+	v, err := sp.StringValue()
+	if err != nil {
+		t.Fatalf("StringValue() error: %v", err)
+	}
+	if v != "hello" {
+		t.Fatalf("StringValue() = %q, want %q", v, "hello")
+	}
+}
+
+func TestStreamParserStringValueWithEscape(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader(`"a\nb"`), 16)
+	if _, err := sp.Next(); err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	v, err := sp.StringValue()
+	if err != nil {
+		t.Fatalf("StringValue() error: %v", err)
+	}
+	if v != "a\nb" {
+		t.Fatalf("StringValue() = %q, want %q", v, "a\nb")
+	}
+}
+
+func TestStreamParserNextSkipsUnreadStringAndNumber(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader(`["hello",42,true]`), 16)
+
+	want := []TokenState{Arr, String, Comma, Number, Comma, True, EndArr}
+	for i, w := range want {
+		tok, err := sp.Next()
+		if err != nil {
+			t.Fatalf("token %d: Next() error: %v", i, err)
+		}
+		if tok != w {
+			t.Fatalf("token %d = %v, want %v", i, tok, w)
+		}
+	}
+	if _, err := sp.Next(); err == nil {
+		t.Fatalf("expected an error (io.EOF) once the document is exhausted")
+	}
+}
+
+func TestStreamParserStringValueSurvivesFurtherReads(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader(`["hello","world9999"]`), 8)
+
+	if _, err := sp.Next(); err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	if _, err := sp.Next(); err != nil {
+		t.Fatalf("Next() error: %v", err)
+	}
+	got, err := sp.StringValue()
+	if err != nil {
+		t.Fatalf("StringValue() error: %v", err)
+	}
+	if got != "hello" {
+		t.Fatalf("StringValue() = %q, want %q", got, "hello")
+	}
+
+	for {
+		tok, err := sp.Next()
+		if err != nil {
+			break
+		}
+		if tok == String {
+			if _, err := sp.StringValue(); err != nil {
+				t.Fatalf("StringValue() error: %v", err)
+			}
+		}
+	}
+
+	if got != "hello" {
+		t.Fatalf("StringValue() result changed after further reads: got %q, want %q", got, "hello")
+	}
+}
+
+func TestStreamParserInt64RejectsNonNumber(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader(`"hello"`), 16)
+	if tok, err := sp.Next(); err != nil || tok != String {
+		t.Fatalf("Next() = %v, %v, want String, nil", tok, err)
+	}
+	if _, err := sp.Int64(); err == nil {
+		t.Fatalf("Int64() on a String token: expected an error, got nil")
+	}
+}
+
+func TestStreamParserFloat64RejectsNonNumber(t *testing.T) {
+	sp := NewStreamParser(strings.NewReader(`true`), 16)
+	if tok, err := sp.Next(); err != nil || tok != True {
+		t.Fatalf("Next() = %v, %v, want True, nil", tok, err)
+	}
+	if _, err := sp.Float64(); err == nil {
+		t.Fatalf("Float64() on a True token: expected an error, got nil")
+	}
+}